@@ -1,6 +1,7 @@
 package sqlez
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -14,17 +15,28 @@ type DBObject interface {
 	Init(interface{}, *DB) error
 	GetAddOn() *DBObjectAddOn
 	CreateTable() error
+	CreateTableContext(ctx context.Context) error
 	GetExisting(params Params) error
+	GetExistingContext(ctx context.Context, params Params) error
 	SaveNew(ignore bool) (int, error)
+	SaveNewContext(ctx context.Context, ignore bool) (int, error)
 	SaveExisting() (int, error)
+	SaveExistingContext(ctx context.Context) (int, error)
 	Refresh() error
+	RefreshContext(ctx context.Context) error
 	Delete() (int, error)
+	DeleteContext(ctx context.Context) (int, error)
 }
 
 type DBObjectAddOn struct {
 	db     *DB
 	parent interface{}
 	meta   *DBObjectMetadata
+	// fkValues holds the raw primary-key value scanned for each `foreign`
+	// column (keyed by DBColumn.field), since the Go field itself is a
+	// pointer-to-struct and can't be set directly from the scanned row.
+	// Preload uses these to batch-fetch and attach the referenced rows.
+	fkValues map[int]interface{}
 }
 
 func (d *DBObjectAddOn) Init(parent interface{}, db *DB) error {
@@ -39,7 +51,7 @@ func (d *DBObjectAddOn) Init(parent interface{}, db *DB) error {
 	d.parent = parent
 	d.db = db
 
-	typ := reflect.TypeOf(val)
+	typ := val.Type()
 
 	// Check if we already have the metadata for this type
 	if t, e := db.objects[typ]; e {
@@ -57,8 +69,15 @@ func (d *DBObjectAddOn) Init(parent interface{}, db *DB) error {
 				continue
 			}
 
+			// the embedded DBObjectAddOn field carries struct-level
+			// directives (unique_together) rather than column directives
+			if val.Type().Field(i).Type == reflect.TypeOf(DBObjectAddOn{}) {
+				parseStructTag(&md, tag)
+				continue
+			}
+
 			split := strings.Split(tag, ",")
-			c := DBColumn{label: split[0]}
+			c := DBColumn{label: split[0], fieldName: val.Type().Field(i).Name}
 
 			for _, v := range split[1:] {
 				vv := strings.Split(v, ":")
@@ -74,6 +93,18 @@ func (d *DBObjectAddOn) Init(parent interface{}, db *DB) error {
 						c.sqlType = vv[1]
 					case "refresh":
 						md.refreshOrderBy = vv[1]
+					case "index":
+						c.indexName = vv[1]
+					case "reverse":
+						// a reverse relation has no column of its own on this
+						// table - it's populated entirely by Preload, via a
+						// batched SELECT against the child table.
+						if val.Field(i).Kind() != reflect.Slice || val.Field(i).Type().Elem().Kind() != reflect.Ptr {
+							return fmt.Errorf("reverse relation must be a slice of pointers to struct")
+						}
+						c.reverse = true
+						c.reverseFK = vv[1]
+						c.relatedType = val.Field(i).Type().Elem().Elem()
 					}
 
 				} else {
@@ -89,12 +120,16 @@ func (d *DBObjectAddOn) Init(parent interface{}, db *DB) error {
 						}
 						// get the type of the struct
 						fkType := val.Field(i).Type()
-						// see if we have that type in our objects map
-						if t, e := db.objects[fkType]; !e {
+						// see if we have that type in our objects map, keyed by
+						// the dereferenced struct type (fkType is *Author, the
+						// map is keyed by Author)
+						if t, e := db.objects[fkType.Elem()]; !e {
 							return fmt.Errorf("foreign table not yet defined")
 						} else {
+							c.foreign = true
 							c.foreignTable = t.table
 							c.foreignKey = t.cols[t.pkey].label
+							c.relatedType = fkType.Elem()
 							md.fkey = i
 						}
 					case v == "unique":
@@ -114,7 +149,17 @@ func (d *DBObjectAddOn) Init(parent interface{}, db *DB) error {
 					}
 				}
 
-				if c.sqlType == "" {
+				if c.reverse {
+					// no physical column, so none of the type bookkeeping
+					// below applies
+					continue
+				}
+
+				if c.sqlType == "" && c.foreign {
+					// the column itself stores the referenced row's primary
+					// key, not the pointer-to-struct Go type
+					c.sqlType = d.db.driver.GetDataType(reflect.TypeOf(0))
+				} else if c.sqlType == "" {
 					c.sqlType = d.db.driver.GetDataType(val.Field(i).Type())
 				}
 
@@ -129,6 +174,12 @@ func (d *DBObjectAddOn) Init(parent interface{}, db *DB) error {
 
 				// find go type
 
+				if c.foreign {
+					// stored and scanned as the referenced row's primary key
+					c.goType = GoInt
+					continue
+				}
+
 				switch val.Field(i).Kind() {
 				case reflect.Struct:
 					if val.Field(i).Type() == d.db.timeType {
@@ -138,8 +189,9 @@ func (d *DBObjectAddOn) Init(parent interface{}, db *DB) error {
 					}
 				case reflect.Float32, reflect.Float64:
 					c.goType = GoFloat
-				case reflect.Int:
+				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 					c.goType = GoInt
+					c.bigInt = val.Field(i).Kind() == reflect.Int64
 				case reflect.String:
 					c.goType = GoString
 				case reflect.Bool:
@@ -149,7 +201,14 @@ func (d *DBObjectAddOn) Init(parent interface{}, db *DB) error {
 				}
 
 			}
+
 			c.field = i
+
+			if c.reverse {
+				md.relations = append(md.relations, c)
+				continue
+			}
+
 			md.cols = append(md.cols, c)
 		}
 
@@ -191,27 +250,35 @@ func (d *DBObjectAddOn) GetAddOn() *DBObjectAddOn {
 
 // CreateTable creates a table in the database according to the DBObjectMetadata
 func (d *DBObjectAddOn) CreateTable() error {
+	return d.CreateTableContext(context.Background())
+}
+
+// CreateTableContext is CreateTable with a caller-supplied context.
+func (d *DBObjectAddOn) CreateTableContext(ctx context.Context) error {
 	if err := validateMetadata(d.meta); err != nil {
 		return err
 	}
 
 	query := d.db.driver.CreateTable(d.meta)
-	d.db.LastQuery = query
 
-	_, err := d.db.DB.Exec(query)
+	_, err := d.db.execContext(ctx, d.db.DB, query)
 	return err
 }
 
 func (d *DBObjectAddOn) GetExisting(params Params) error {
+	return d.GetExistingContext(context.Background(), params)
+}
+
+// GetExistingContext is GetExisting with a caller-supplied context.
+func (d *DBObjectAddOn) GetExistingContext(ctx context.Context, params Params) error {
 	if err := validateMetadata(d.meta); err != nil {
 		return err
 	}
 	params.Limit = 1
 
-	query := d.db.driver.Select(d, params)
-	d.db.LastQuery = query
+	query, args := d.db.driver.Select(d, params)
 
-	rows, err := d.db.DB.Query(query)
+	rows, err := d.db.queryContext(ctx, d.db.DB, query, args...)
 	if err != nil {
 		return err
 	}
@@ -221,65 +288,103 @@ func (d *DBObjectAddOn) GetExisting(params Params) error {
 		return fmt.Errorf("no rows returned matching criteria")
 	}
 
-	_, err = d.populate(rows)
-	return err
+	if _, err = d.populate(rows); err != nil {
+		return err
+	}
+
+	if len(params.Preload) > 0 {
+		return preloadRelations(d.db, ctx, d.db.DB, []DBObject{d.parent.(DBObject)}, params.Preload)
+	}
+	return nil
+}
+
+func (d *DBObjectAddOn) SaveNew(ignore bool) (int, error) {
+	return d.SaveNewContext(context.Background(), ignore)
 }
 
-func (d *DBObjectAddOn) SaveNew(ignore bool) (n int, err error) {
+// SaveNewContext is SaveNew with a caller-supplied context.
+func (d *DBObjectAddOn) SaveNewContext(ctx context.Context, ignore bool) (n int, err error) {
 	if err = validateMetadata(d.meta); err != nil {
 		return
 	}
 
-	query, vals := d.db.driver.InsertIgnore(d, ignore)
-	d.db.LastQuery = query
+	obj := d.parent.(DBObject)
+	if err = d.db.runHooks(ctx, obj, hookBeforeInsert); err != nil {
+		return
+	}
+
+	query, vals, apply := d.db.driver.InsertIgnore(d, ignore)
 
 	var res sql.Result
-	res, err = d.db.DB.Exec(query, vals...)
+	res, err = d.db.execContext(ctx, d.db.DB, query, vals...)
 	if err != nil {
 		return
 	}
 	if nr, e := res.RowsAffected(); e == nil {
 		n = int(nr)
 	}
+	if apply != nil {
+		apply()
+	}
+
+	err = d.db.runHooks(ctx, obj, hookAfterInsert)
 	return
 }
 
-func (d *DBObjectAddOn) SaveExisting() (n int, err error) {
+func (d *DBObjectAddOn) SaveExisting() (int, error) {
+	return d.SaveExistingContext(context.Background())
+}
+
+// SaveExistingContext is SaveExisting with a caller-supplied context.
+func (d *DBObjectAddOn) SaveExistingContext(ctx context.Context) (n int, err error) {
 	if err = validateMetadata(d.meta); err != nil {
 		return
 	}
 
-	query, vals := d.db.driver.Update(d)
-	d.db.LastQuery = query
+	obj := d.parent.(DBObject)
+	if err = d.db.runHooks(ctx, obj, hookBeforeUpdate); err != nil {
+		return
+	}
+
+	query, vals, apply := d.db.driver.Update(d)
 
 	var res sql.Result
-	res, err = d.db.DB.Exec(query, vals...)
+	res, err = d.db.execContext(ctx, d.db.DB, query, vals...)
 	if err != nil {
 		return
 	}
 	if nr, e := res.RowsAffected(); e == nil {
 		n = int(nr)
 	}
+	if apply != nil {
+		apply()
+	}
+
+	err = d.db.runHooks(ctx, obj, hookAfterUpdate)
 	return
 }
 
 func (d *DBObjectAddOn) Refresh() error {
+	return d.RefreshContext(context.Background())
+}
+
+// RefreshContext is Refresh with a caller-supplied context.
+func (d *DBObjectAddOn) RefreshContext(ctx context.Context) error {
 
 	if err := validateMetadata(d.meta); err != nil {
 		return err
 	}
 
 	p := Params{
-		Where:   fmt.Sprintf("%s = ?", d.meta.cols[d.meta.pkey].label),
+		Where:   fmt.Sprintf("%s = %s", d.meta.cols[d.meta.pkey].label, d.db.driver.Placeholder(1)),
 		Limit:   1,
 		OrderBy: d.meta.refreshOrderBy,
 	}
 
-	query := d.db.driver.Select(d, p)
-	d.db.LastQuery = query
+	query, args := d.db.driver.Select(d, p)
 
 	pk := reflect.ValueOf(d.parent).Elem().Field(d.meta.pkey).Interface()
-	rows, err := d.db.DB.Query(query, pk)
+	rows, err := d.db.queryContext(ctx, d.db.DB, query, append(args, pk)...)
 	if err != nil {
 		return err
 	}
@@ -312,42 +417,19 @@ func (d *DBObjectAddOn) populate(rows *sql.Rows) (n int, err error) {
 	}
 
 	for i, c := range d.meta.cols {
-		if c.json {
-			// unmarshal
-			if err = json.Unmarshal(values[i].([]byte), dest.Field(i).Addr().Interface()); err != nil {
-				err = fmt.Errorf("error unmarshalling column flagged as json: %s", err)
-				return
+		if c.foreign {
+			// the Go field is a pointer to the referenced struct, not an
+			// int, so the scanned FK value is stashed for Preload rather
+			// than written to the field directly.
+			if d.fkValues == nil {
+				d.fkValues = make(map[int]interface{})
 			}
+			d.fkValues[c.field] = values[i]
 			continue
 		}
 
-		switch c.goType {
-		case GoInt:
-			dest.Field(c.field).SetInt(values[i].(int64))
-		case GoFloat:
-			dest.Field(c.field).SetFloat(values[i].(float64))
-		case GoString:
-			dest.Field(c.field).SetString(values[i].(string))
-		case GoBool:
-			dest.Field(c.field).SetBool(values[i].(bool))
-		case GoTime:
-			var v int64
-			switch values[i].(type) {
-			case string:
-				if v, err = strconv.ParseInt(values[i].(string), 10, 64); err != nil {
-					err = fmt.Errorf("error parsing time: %s", err)
-					return
-				}
-			case int64:
-				v = values[i].(int64)
-			default:
-				err = fmt.Errorf("invalid time type")
-				return
-			}
-
-			dest.Field(c.field).Set(reflect.ValueOf(time.Unix(v, 0)))
-		case GoStruct:
-			err = fmt.Errorf("structs must be marked as json or ignored")
+		if err = assignColumnValue(dest, c, values[i]); err != nil {
+			return
 		}
 	}
 
@@ -357,21 +439,78 @@ func (d *DBObjectAddOn) populate(rows *sql.Rows) (n int, err error) {
 	return
 }
 
-func (d *DBObjectAddOn) Delete() (n int, err error) {
+// assignColumnValue converts a single value scanned off a *sql.Rows and
+// assigns it to c's field on dest, the same conversion populate and the
+// Raw scan helpers both need. c.foreign columns are handled by the callers
+// before this is reached; c.json columns are unmarshalled here.
+func assignColumnValue(dest reflect.Value, c DBColumn, value interface{}) error {
+	if c.json {
+		b, ok := value.([]byte)
+		if !ok {
+			return fmt.Errorf("error unmarshalling column flagged as json: unexpected type %T", value)
+		}
+		if err := json.Unmarshal(b, dest.Field(c.field).Addr().Interface()); err != nil {
+			return fmt.Errorf("error unmarshalling column flagged as json: %s", err)
+		}
+		return nil
+	}
+
+	switch c.goType {
+	case GoInt:
+		dest.Field(c.field).SetInt(value.(int64))
+	case GoFloat:
+		dest.Field(c.field).SetFloat(value.(float64))
+	case GoString:
+		dest.Field(c.field).SetString(value.(string))
+	case GoBool:
+		dest.Field(c.field).SetBool(value.(bool))
+	case GoTime:
+		var v int64
+		switch tv := value.(type) {
+		case string:
+			n, err := strconv.ParseInt(tv, 10, 64)
+			if err != nil {
+				return fmt.Errorf("error parsing time: %s", err)
+			}
+			v = n
+		case int64:
+			v = tv
+		default:
+			return fmt.Errorf("invalid time type")
+		}
+		dest.Field(c.field).Set(reflect.ValueOf(time.Unix(v, 0)))
+	case GoStruct:
+		return fmt.Errorf("structs must be marked as json or ignored")
+	}
+	return nil
+}
+
+func (d *DBObjectAddOn) Delete() (int, error) {
+	return d.DeleteContext(context.Background())
+}
+
+// DeleteContext is Delete with a caller-supplied context.
+func (d *DBObjectAddOn) DeleteContext(ctx context.Context) (n int, err error) {
 	if err = validateMetadata(d.meta); err != nil {
 		return
 	}
 
+	obj := d.parent.(DBObject)
+	if err = d.db.runHooks(ctx, obj, hookBeforeDelete); err != nil {
+		return
+	}
+
 	query, val := d.db.driver.Delete(d)
-	d.db.LastQuery = query
 
 	var res sql.Result
-	res, err = d.db.DB.Exec(query, val)
+	res, err = d.db.execContext(ctx, d.db.DB, query, val)
 	if err != nil {
 		return
 	}
 	if nr, e := res.RowsAffected(); e == nil {
 		n = int(nr)
 	}
+
+	err = d.db.runHooks(ctx, obj, hookAfterDelete)
 	return
 }
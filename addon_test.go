@@ -0,0 +1,73 @@
+package sqlez
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type attachTestAuthor struct {
+	ID   int    `db:"id,primary,table:authors"`
+	Name string `db:"name"`
+	DBObjectAddOn
+}
+
+type attachTestPost struct {
+	ID     int               `db:"id,primary,table:posts"`
+	Title  string            `db:"title"`
+	Author *attachTestAuthor `db:"author_id,foreign"`
+	DBObjectAddOn
+}
+
+// TestAttachTwoRelatedTypes attaches two distinct types where the second has
+// a foreign field pointing at the first, then checks that Preload can find
+// the relation.
+// It guards against the db.objects cache key colliding across types (which
+// made every attached type alias the first one's metadata) and against the
+// foreign-field type lookup missing because it indexed the map with the
+// pointer type instead of its element type.
+func TestAttachTwoRelatedTypes(t *testing.T) {
+	db := &DB{
+		driver:   Sqlite,
+		objects:  make(map[reflect.Type]DBObjectMetadata),
+		dbTag:    "db",
+		timeType: reflect.TypeOf(time.Time{}),
+	}
+
+	author := &attachTestAuthor{}
+	if err := db.Attach(author); err != nil {
+		t.Fatalf("attach author: %v", err)
+	}
+	post := &attachTestPost{}
+	if err := db.Attach(post); err != nil {
+		t.Fatalf("attach post: %v", err)
+	}
+
+	authorMeta := author.GetAddOn().meta
+	postMeta := post.GetAddOn().meta
+
+	if authorMeta.table != "authors" {
+		t.Fatalf("author metadata got clobbered: table = %q, want %q", authorMeta.table, "authors")
+	}
+	if postMeta.table != "posts" {
+		t.Fatalf("post metadata got clobbered: table = %q, want %q", postMeta.table, "posts")
+	}
+
+	postSQL := Sqlite.CreateTable(postMeta)
+	if !strings.Contains(postSQL, "posts") {
+		t.Fatalf("CreateTable for post didn't reference its own table: %s", postSQL)
+	}
+	if strings.Contains(postSQL, "authors (") {
+		t.Fatalf("CreateTable for post emitted author's schema instead of its own: %s", postSQL)
+	}
+
+	fkCol := postMeta.cols[postMeta.fkey]
+	if !fkCol.foreign || fkCol.foreignTable != "authors" || fkCol.relatedType != reflect.TypeOf(attachTestAuthor{}) {
+		t.Fatalf("foreign column not resolved against authorMeta: %+v", fkCol)
+	}
+
+	if _, ok := findRelation(postMeta, "Author"); !ok {
+		t.Fatalf("preloadRelations can't find the Author relation on Post")
+	}
+}
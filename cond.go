@@ -0,0 +1,199 @@
+package sqlez
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// condTerm is one term in a Cond, joined to the previous term with AND
+// unless or is set. It's either a field/operator/value leaf, or - if group
+// is set - a parenthesized sub-Cond built with AndGroup/OrGroup.
+type condTerm struct {
+	field string
+	op    string
+	val   interface{}
+	or    bool
+	group *Cond
+}
+
+// Cond is a typed WHERE-expression builder, compiled to driver-specific SQL
+// with real placeholders instead of raw string interpolation. Field names
+// take Django/Beego-style operator suffixes - "age__gte", "name__icontains" -
+// a bare field name is equivalent to "field__exact". Supported suffixes:
+// exact, iexact, contains, icontains, startswith, endswith, gt, gte, lt, lte,
+// in, between, isnull.
+//
+// And/Or chain flat, left-to-right, same precedence as raw SQL - mixing them
+// changes the grouping exactly like it would in a hand-written WHERE clause.
+// Use AndGroup/OrGroup to parenthesize a sub-Cond, e.g. the "active-or-pending
+// items owned by bob" case:
+//
+//	Q("owner__exact", "bob").AndGroup(Q("status__exact", "active").Or("status__exact", "pending"))
+type Cond struct {
+	terms []condTerm
+}
+
+// Q starts a new Cond with a single field/operator/value term, e.g.
+// Q("name__icontains", "bob").And("age__gte", 18).Or("id__in", []int{1, 2}).
+func Q(fieldOp string, val interface{}) *Cond {
+	return (&Cond{}).And(fieldOp, val)
+}
+
+// And appends a term joined to the previous one with AND.
+func (c *Cond) And(fieldOp string, val interface{}) *Cond {
+	field, op := splitFieldOp(fieldOp)
+	c.terms = append(c.terms, condTerm{field: field, op: op, val: val})
+	return c
+}
+
+// Or appends a term joined to the previous one with OR.
+func (c *Cond) Or(fieldOp string, val interface{}) *Cond {
+	field, op := splitFieldOp(fieldOp)
+	c.terms = append(c.terms, condTerm{field: field, op: op, val: val, or: true})
+	return c
+}
+
+// AndGroup appends sub as a single parenthesized term, joined to the
+// previous one with AND, e.g. Q("a", 1).AndGroup(Q("b", 2).Or("c", 3))
+// compiles to `"a" = ? AND ("b" = ? OR "c" = ?)` with the SQLite driver.
+func (c *Cond) AndGroup(sub *Cond) *Cond {
+	c.terms = append(c.terms, condTerm{group: sub})
+	return c
+}
+
+// OrGroup appends sub as a single parenthesized term, joined to the
+// previous one with OR, e.g. Q("a", 1).OrGroup(Q("b", 2).And("c", 3))
+// compiles to `"a" = ? OR ("b" = ? AND "c" = ?)` with the SQLite driver.
+func (c *Cond) OrGroup(sub *Cond) *Cond {
+	c.terms = append(c.terms, condTerm{group: sub, or: true})
+	return c
+}
+
+// IsNull appends an `IS NULL` term joined with AND.
+func (c *Cond) IsNull(field string) *Cond {
+	c.terms = append(c.terms, condTerm{field: field, op: "isnull", val: true})
+	return c
+}
+
+func splitFieldOp(fieldOp string) (field, op string) {
+	if i := strings.LastIndex(fieldOp, "__"); i >= 0 {
+		switch fieldOp[i+2:] {
+		case "exact", "iexact", "contains", "icontains", "startswith", "endswith",
+			"gt", "gte", "lt", "lte", "in", "between", "isnull":
+			return fieldOp[:i], fieldOp[i+2:]
+		}
+	}
+	return fieldOp, "exact"
+}
+
+// compile renders the Cond to a SQL fragment (without the leading "WHERE")
+// plus its bind args, in term order, using driver for identifier quoting,
+// placeholders and case-insensitive LIKE.
+func (c *Cond) compile(driver DBDriver) (string, []interface{}) {
+	var args []interface{}
+	sql := c.compileInto(driver, &args)
+	return sql, args
+}
+
+// compileInto renders c into sb's return value, appending its bind args to
+// *args so placeholder numbering (needed by dialects like Postgres's $N)
+// stays correct across a nested AndGroup/OrGroup sub-Cond.
+func (c *Cond) compileInto(driver DBDriver, args *[]interface{}) string {
+	var sb strings.Builder
+
+	for i, t := range c.terms {
+		if i > 0 {
+			if t.or {
+				sb.WriteString(" OR ")
+			} else {
+				sb.WriteString(" AND ")
+			}
+		}
+
+		if t.group != nil {
+			sb.WriteString("(")
+			sb.WriteString(t.group.compileInto(driver, args))
+			sb.WriteString(")")
+			continue
+		}
+
+		col := driver.QuoteIdent(t.field)
+
+		switch t.op {
+		case "exact":
+			sb.WriteString(fmt.Sprintf("%s = %s", col, driver.Placeholder(len(*args)+1)))
+			*args = append(*args, t.val)
+		case "iexact":
+			sb.WriteString(fmt.Sprintf("LOWER(%s) = LOWER(%s)", col, driver.Placeholder(len(*args)+1)))
+			*args = append(*args, t.val)
+		case "contains":
+			sb.WriteString(fmt.Sprintf("%s LIKE %s", col, driver.Placeholder(len(*args)+1)))
+			*args = append(*args, likePattern(t.val, true, true))
+		case "icontains":
+			sb.WriteString(fmt.Sprintf(driver.ILike(), col, driver.Placeholder(len(*args)+1)))
+			*args = append(*args, likePattern(t.val, true, true))
+		case "startswith":
+			sb.WriteString(fmt.Sprintf("%s LIKE %s", col, driver.Placeholder(len(*args)+1)))
+			*args = append(*args, likePattern(t.val, false, true))
+		case "endswith":
+			sb.WriteString(fmt.Sprintf("%s LIKE %s", col, driver.Placeholder(len(*args)+1)))
+			*args = append(*args, likePattern(t.val, true, false))
+		case "gt":
+			sb.WriteString(fmt.Sprintf("%s > %s", col, driver.Placeholder(len(*args)+1)))
+			*args = append(*args, t.val)
+		case "gte":
+			sb.WriteString(fmt.Sprintf("%s >= %s", col, driver.Placeholder(len(*args)+1)))
+			*args = append(*args, t.val)
+		case "lt":
+			sb.WriteString(fmt.Sprintf("%s < %s", col, driver.Placeholder(len(*args)+1)))
+			*args = append(*args, t.val)
+		case "lte":
+			sb.WriteString(fmt.Sprintf("%s <= %s", col, driver.Placeholder(len(*args)+1)))
+			*args = append(*args, t.val)
+		case "in":
+			vals := toInterfaceSlice(t.val)
+			phs := make([]string, len(vals))
+			for j, v := range vals {
+				phs[j] = driver.Placeholder(len(*args) + 1)
+				*args = append(*args, v)
+			}
+			sb.WriteString(fmt.Sprintf("%s IN (%s)", col, strings.Join(phs, ", ")))
+		case "between":
+			vals := toInterfaceSlice(t.val)
+			low := driver.Placeholder(len(*args) + 1)
+			*args = append(*args, vals[0])
+			high := driver.Placeholder(len(*args) + 1)
+			*args = append(*args, vals[1])
+			sb.WriteString(fmt.Sprintf("%s BETWEEN %s AND %s", col, low, high))
+		case "isnull":
+			if t.val.(bool) {
+				sb.WriteString(fmt.Sprintf("%s IS NULL", col))
+			} else {
+				sb.WriteString(fmt.Sprintf("%s IS NOT NULL", col))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+func likePattern(val interface{}, pre, post bool) string {
+	s := fmt.Sprintf("%v", val)
+	if pre {
+		s = "%" + s
+	}
+	if post {
+		s = s + "%"
+	}
+	return s
+}
+
+func toInterfaceSlice(val interface{}) []interface{} {
+	rv := reflect.ValueOf(val)
+	out := make([]interface{}, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+package sqlez
+
+import (
+	"context"
+	"reflect"
+)
+
+// HookFunc is a callback registered with OnBefore*/OnAfter*. obj is the
+// DBObject the operation ran (or is about to run) against.
+type HookFunc func(ctx context.Context, obj DBObject) error
+
+type hookKind int
+
+const (
+	hookBeforeInsert hookKind = iota
+	hookAfterInsert
+	hookBeforeUpdate
+	hookAfterUpdate
+	hookBeforeDelete
+	hookAfterDelete
+)
+
+// typeHooks holds the hooks registered for a single DBObject type.
+type typeHooks struct {
+	hooks map[hookKind][]HookFunc
+}
+
+func (d *DB) addHook(sample DBObject, kind hookKind, fn HookFunc) {
+	typ := reflect.TypeOf(sample).Elem()
+
+	d.hooksMu.Lock()
+	defer d.hooksMu.Unlock()
+	if d.hooks == nil {
+		d.hooks = make(map[reflect.Type]*typeHooks)
+	}
+	th, ok := d.hooks[typ]
+	if !ok {
+		th = &typeHooks{hooks: make(map[hookKind][]HookFunc)}
+		d.hooks[typ] = th
+	}
+	th.hooks[kind] = append(th.hooks[kind], fn)
+}
+
+// runHooks runs every hook of kind registered for obj's type, in
+// registration order, stopping at the first error.
+func (d *DB) runHooks(ctx context.Context, obj DBObject, kind hookKind) error {
+	typ := reflect.TypeOf(obj).Elem()
+
+	d.hooksMu.RLock()
+	th, ok := d.hooks[typ]
+	d.hooksMu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	for _, fn := range th.hooks[kind] {
+		if err := fn(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OnBeforeInsert registers fn to run before every SaveNew/SaveNewContext
+// call for sample's type, aborting the insert if fn returns an error.
+// sample is only used to identify the type - a zero value (e.g. &User{})
+// is enough.
+func (d *DB) OnBeforeInsert(sample DBObject, fn HookFunc) {
+	d.addHook(sample, hookBeforeInsert, fn)
+}
+
+// OnAfterInsert registers fn to run after a successful
+// SaveNew/SaveNewContext call for sample's type.
+func (d *DB) OnAfterInsert(sample DBObject, fn HookFunc) {
+	d.addHook(sample, hookAfterInsert, fn)
+}
+
+// OnBeforeUpdate registers fn to run before every
+// SaveExisting/SaveExistingContext call for sample's type, aborting the
+// update if fn returns an error.
+func (d *DB) OnBeforeUpdate(sample DBObject, fn HookFunc) {
+	d.addHook(sample, hookBeforeUpdate, fn)
+}
+
+// OnAfterUpdate registers fn to run after a successful
+// SaveExisting/SaveExistingContext call for sample's type.
+func (d *DB) OnAfterUpdate(sample DBObject, fn HookFunc) {
+	d.addHook(sample, hookAfterUpdate, fn)
+}
+
+// OnBeforeDelete registers fn to run before every Delete/DeleteContext call
+// for sample's type, aborting the delete if fn returns an error.
+func (d *DB) OnBeforeDelete(sample DBObject, fn HookFunc) {
+	d.addHook(sample, hookBeforeDelete, fn)
+}
+
+// OnAfterDelete registers fn to run after a successful Delete/DeleteContext
+// call for sample's type.
+func (d *DB) OnAfterDelete(sample DBObject, fn HookFunc) {
+	d.addHook(sample, hookAfterDelete, fn)
+}
@@ -0,0 +1,78 @@
+package sqlez
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Logger receives a record of every query sqlez runs through a DB that has
+// had SetLogger called on it. The schema-introspection queries SyncSchema
+// has the driver run (GetTables, GetColumns, GetIndexes) go straight to the
+// *sql.DB and are not logged.
+type Logger interface {
+	LogQuery(ctx context.Context, query string, args []interface{}, dur time.Duration, err error)
+}
+
+// StdLogger logs every query through the standard library's log package.
+type StdLogger struct {
+	*log.Logger
+}
+
+// NewStdLogger wraps l as a Logger. If l is nil, it logs through
+// log.Default().
+func NewStdLogger(l *log.Logger) *StdLogger {
+	if l == nil {
+		l = log.Default()
+	}
+	return &StdLogger{l}
+}
+
+func (s *StdLogger) LogQuery(ctx context.Context, query string, args []interface{}, dur time.Duration, err error) {
+	if err != nil {
+		s.Printf("sqlez: %s %v (%s): %s", query, args, dur, err)
+		return
+	}
+	s.Printf("sqlez: %s %v (%s)", query, args, dur)
+}
+
+// SlowLogger wraps another Logger, forwarding a query to it only if the
+// query took at least Threshold to run or it returned an error.
+type SlowLogger struct {
+	Logger
+	Threshold time.Duration
+}
+
+// NewSlowLogger returns a SlowLogger that forwards queries that took at
+// least threshold (or that errored) to next.
+func NewSlowLogger(threshold time.Duration, next Logger) *SlowLogger {
+	return &SlowLogger{Logger: next, Threshold: threshold}
+}
+
+func (s *SlowLogger) LogQuery(ctx context.Context, query string, args []interface{}, dur time.Duration, err error) {
+	if err == nil && dur < s.Threshold {
+		return
+	}
+	s.Logger.LogQuery(ctx, query, args, dur, err)
+}
+
+// SetLogger registers l to receive every query sqlez executes through d
+// (except the introspection queries noted on Logger), or clears logging if
+// l is nil. Safe for concurrent use.
+func (d *DB) SetLogger(l Logger) {
+	d.loggerMu.Lock()
+	d.logger = l
+	d.loggerMu.Unlock()
+}
+
+func (d *DB) getLogger() Logger {
+	d.loggerMu.RLock()
+	defer d.loggerMu.RUnlock()
+	return d.logger
+}
+
+func (d *DB) logQuery(ctx context.Context, query string, args []interface{}, dur time.Duration, err error) {
+	if l := d.getLogger(); l != nil {
+		l.LogQuery(ctx, query, args, dur, err)
+	}
+}
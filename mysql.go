@@ -1,6 +1,7 @@
 package sqlez
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -42,8 +43,8 @@ func (d MySQLDriver) CreateTable(data *DBObjectMetadata) string {
 		}
 
 		auto := ""
-		if col.autoinc || col.primary {
-			auto = " AUTOINCREMENT"
+		if col.autoinc {
+			auto = " AUTO_INCREMENT"
 		}
 
 		def := ""
@@ -56,7 +57,7 @@ func (d MySQLDriver) CreateTable(data *DBObjectMetadata) string {
 			prop = " " + col.colProp
 		}
 
-		columns = append(columns, fmt.Sprintf("%s %s%s%s%s%s", col.label, col.sqlType, pk, auto, def, prop))
+		columns = append(columns, fmt.Sprintf("%s %s%s%s%s%s", col.label, col.sqlType, auto, pk, def, prop))
 	}
 
 	if data.fkey >= 0 {
@@ -67,10 +68,15 @@ func (d MySQLDriver) CreateTable(data *DBObjectMetadata) string {
 	return fmt.Sprintf("CREATE TABLE %s (%s)", data.table, strings.Join(columns, ", "))
 }
 
-// InsertIgnore returns a query string and a slice of values to be used with it
-func (d MySQLDriver) InsertIgnore(data *DBObjectAddOn, ignore bool) (query string, vals []interface{}) {
+// InsertIgnore returns a query string and a slice of values to be used with
+// it, plus an apply func that assigns the created/updated timestamps to the
+// struct. apply must not be called until the insert is known to have
+// committed.
+func (d MySQLDriver) InsertIgnore(data *DBObjectAddOn, ignore bool) (query string, vals []interface{}, apply func()) {
 
 	val := reflect.ValueOf(data.parent).Elem()
+	now := time.Now()
+	var pending []func()
 
 	var columns []string
 	for i, col := range data.meta.cols {
@@ -78,16 +84,17 @@ func (d MySQLDriver) InsertIgnore(data *DBObjectAddOn, ignore bool) (query strin
 			continue
 		}
 
-		if col.created {
-			val.Field(i).Set(reflect.ValueOf(time.Now()))
-		}
-		if col.updated {
-			val.Field(i).Set(reflect.ValueOf(time.Now()))
+		field := val.Field(i)
+		colVal := field.Interface()
+
+		if col.created || col.updated {
+			colVal = now
+			pending = append(pending, func() { field.Set(reflect.ValueOf(now)) })
 		}
 
 		columns = append(columns, col.label)
 		if col.json {
-			if j, err := json.Marshal(val.Field(i).Interface()); err != nil {
+			if j, err := json.Marshal(colVal); err != nil {
 				fmt.Printf("err marshalling json: %s\n", err)
 			} else {
 				vals = append(vals, string(j))
@@ -96,25 +103,34 @@ func (d MySQLDriver) InsertIgnore(data *DBObjectAddOn, ignore bool) (query strin
 		}
 
 		if col.goType == GoTime {
-			vals = append(vals, val.Field(i).Interface().(time.Time).Unix())
+			vals = append(vals, colVal.(time.Time).Unix())
 			continue
 		}
 
-		vals = append(vals, val.Field(i).Interface())
+		vals = append(vals, colVal)
 	}
 
 	ig := ""
 	if ignore {
-		ig = " OR IGNORE"
+		ig = "IGNORE "
 	}
 
-	query = fmt.Sprintf("INSERT%s INTO %s (%s) VALUES (%s)", ig, data.meta.table, strings.Join(columns, ", "), strings.Repeat("?, ", len(columns)-1)+"?")
+	query = fmt.Sprintf("INSERT %sINTO %s (%s) VALUES (%s)", ig, data.meta.table, strings.Join(columns, ", "), strings.Repeat("?, ", len(columns)-1)+"?")
+	apply = func() {
+		for _, p := range pending {
+			p()
+		}
+	}
 	return
 }
 
-// Update returns a query string and a slice of values to be used with it
-func (d MySQLDriver) Update(data *DBObjectAddOn) (query string, vals []interface{}) {
+// Update returns a query string and a slice of values to be used with it,
+// plus an apply func that assigns the updated timestamp to the struct. apply
+// must not be called until the update is known to have committed.
+func (d MySQLDriver) Update(data *DBObjectAddOn) (query string, vals []interface{}, apply func()) {
 	val := reflect.ValueOf(data.parent).Elem()
+	now := time.Now()
+	var pending []func()
 
 	var where string
 	var whereval interface{}
@@ -127,29 +143,39 @@ func (d MySQLDriver) Update(data *DBObjectAddOn) (query string, vals []interface
 			continue
 		}
 
+		field := val.Field(i)
+		colVal := field.Interface()
+
 		if col.updated {
-			val.Field(i).Set(reflect.ValueOf(time.Now()))
+			colVal = now
+			pending = append(pending, func() { field.Set(reflect.ValueOf(now)) })
 		}
 
 		columns = append(columns, col.label)
 
 		if col.goType == GoTime {
-			vals = append(vals, val.Field(i).Interface().(time.Time).Unix())
+			vals = append(vals, colVal.(time.Time).Unix())
 			continue
 		}
-		vals = append(vals, val.Field(i).Interface())
+		vals = append(vals, colVal)
 	}
 
 	vals = append(vals, whereval)
 	query = fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?", data.meta.table, strings.Join(columns, "= ?, ")+"= ?", where)
+	apply = func() {
+		for _, p := range pending {
+			p()
+		}
+	}
 	return
 }
 
 // Select
-func (d MySQLDriver) Select(data *DBObjectAddOn, params Params) (query string) {
+func (d MySQLDriver) Select(data *DBObjectAddOn, params Params) (query string, vals []interface{}) {
 	var where, order, limit string
-	if params.Where != "" {
-		where = " WHERE " + params.Where
+	if whereSQL, whereArgs := resolveWhere(d, params.Where); whereSQL != "" {
+		where = " WHERE " + whereSQL
+		vals = whereArgs
 	}
 	if params.OrderBy != "" {
 		order = " ORDER BY " + params.OrderBy
@@ -162,9 +188,114 @@ func (d MySQLDriver) Select(data *DBObjectAddOn, params Params) (query string) {
 	return
 }
 
+// QuoteIdent quotes a column or table name for MySQL.
+func (d MySQLDriver) QuoteIdent(name string) string {
+	return "`" + name + "`"
+}
+
+// Placeholder returns the MySQL bind placeholder for the i'th argument
+// (MySQL placeholders aren't numbered, so i is ignored).
+func (d MySQLDriver) Placeholder(i int) string {
+	return "?"
+}
+
+// ILike returns a case-insensitive LIKE template with two %s verbs for the
+// column expression and the placeholder.
+func (d MySQLDriver) ILike() string {
+	return "LOWER(%s) LIKE LOWER(%s)"
+}
+
 // Delete
 func (d MySQLDriver) Delete(data *DBObjectAddOn) (query string, vals interface{}) {
 	query = fmt.Sprintf("DELETE FROM %s WHERE %s = ?", data.meta.table, data.meta.cols[data.meta.pkey].label)
 	vals = reflect.ValueOf(data.parent).Elem().Field(data.meta.pkey).Interface()
 	return
 }
+
+// GetTables lists the tables in the connected MySQL database.
+func (d MySQLDriver) GetTables(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("SHOW TABLES")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+// GetColumns introspects a table's columns via INFORMATION_SCHEMA.
+func (d MySQLDriver) GetColumns(db *sql.DB, table string) ([]ColumnInfo, error) {
+	rows, err := db.Query(
+		"SELECT COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_KEY FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?",
+		table,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cols []ColumnInfo
+	for rows.Next() {
+		var name, sqlType, nullable, key string
+		if err := rows.Scan(&name, &sqlType, &nullable, &key); err != nil {
+			return nil, err
+		}
+		cols = append(cols, ColumnInfo{
+			Name:     name,
+			Type:     sqlType,
+			Nullable: nullable == "YES",
+			Primary:  key == "PRI",
+		})
+	}
+	return cols, rows.Err()
+}
+
+// AlterAddColumn returns the ALTER TABLE statement that adds col to table.
+func (d MySQLDriver) AlterAddColumn(table string, col DBColumn) string {
+	def := ""
+	if col.def != "" {
+		def = " DEFAULT " + col.def
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s%s", table, col.label, col.sqlType, def)
+}
+
+// GetIndexes lists the distinct index names already defined on table.
+func (d MySQLDriver) GetIndexes(db *sql.DB, table string) ([]string, error) {
+	rows, err := db.Query(
+		"SELECT DISTINCT INDEX_NAME FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?",
+		table,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// CreateIndex returns the statement that creates a (optionally unique) index
+// on table. MySQL's CREATE INDEX has no IF NOT EXISTS clause, so callers
+// must check GetIndexes themselves before calling this.
+func (d MySQLDriver) CreateIndex(table, name string, columns []string, unique bool) string {
+	kind := "INDEX"
+	if unique {
+		kind = "UNIQUE INDEX"
+	}
+	return fmt.Sprintf("CREATE %s %s ON %s (%s)", kind, name, table, strings.Join(columns, ", "))
+}
@@ -0,0 +1,308 @@
+package sqlez
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type PostgresDriver struct{}
+
+func (d PostgresDriver) GetDataType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "TEXT"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Bool:
+		return "INTEGER"
+	case reflect.Float32, reflect.Float64:
+		return "DOUBLE PRECISION"
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			return "TIMESTAMP"
+		}
+	}
+	return "TEXT"
+}
+
+func (d PostgresDriver) GetName() string {
+	return "postgres"
+}
+
+func (d PostgresDriver) CreateTable(data *DBObjectMetadata) string {
+
+	var columns []string
+	for _, col := range data.cols {
+		sqlType := col.sqlType
+		if col.autoinc && col.goType == GoInt {
+			sqlType = "SERIAL"
+			if col.bigInt {
+				sqlType = "BIGSERIAL"
+			}
+		}
+
+		pk := ""
+		if col.primary {
+			pk = " PRIMARY KEY"
+		}
+
+		def := ""
+		if col.def != "" {
+			def = " DEFAULT " + col.def
+		}
+
+		prop := ""
+		if col.colProp != "" {
+			prop = " " + col.colProp
+		}
+
+		columns = append(columns, fmt.Sprintf("%s %s%s%s%s", col.label, sqlType, pk, def, prop))
+	}
+
+	if data.fkey >= 0 {
+		fk := data.cols[data.fkey]
+		columns = append(columns, fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s)", fk.label, fk.foreignTable, fk.foreignKey))
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (%s)", data.table, strings.Join(columns, ", "))
+}
+
+// InsertIgnore returns a query string and a slice of values to be used with
+// it, plus an apply func that assigns the created/updated timestamps to the
+// struct. apply must not be called until the insert is known to have
+// committed. ignore is implemented with ON CONFLICT DO NOTHING, since
+// Postgres has no INSERT IGNORE / INSERT OR IGNORE equivalent.
+func (d PostgresDriver) InsertIgnore(data *DBObjectAddOn, ignore bool) (query string, vals []interface{}, apply func()) {
+
+	val := reflect.ValueOf(data.parent).Elem()
+	now := time.Now()
+	var pending []func()
+
+	var columns []string
+	for i, col := range data.meta.cols {
+		if col.primary {
+			continue
+		}
+
+		field := val.Field(i)
+		colVal := field.Interface()
+
+		if col.created || col.updated {
+			colVal = now
+			pending = append(pending, func() { field.Set(reflect.ValueOf(now)) })
+		}
+
+		columns = append(columns, col.label)
+		if col.json {
+			if j, err := json.Marshal(colVal); err != nil {
+				fmt.Printf("err marshalling json: %s\n", err)
+			} else {
+				vals = append(vals, string(j))
+				continue
+			}
+		}
+
+		if col.goType == GoTime {
+			vals = append(vals, colVal.(time.Time).Unix())
+			continue
+		}
+
+		vals = append(vals, colVal)
+	}
+
+	placeholders := make([]string, len(columns))
+	for i := range placeholders {
+		placeholders[i] = d.Placeholder(i + 1)
+	}
+
+	conflict := ""
+	if ignore {
+		conflict = " ON CONFLICT DO NOTHING"
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)%s", data.meta.table, strings.Join(columns, ", "), strings.Join(placeholders, ", "), conflict)
+	apply = func() {
+		for _, p := range pending {
+			p()
+		}
+	}
+	return
+}
+
+// Update returns a query string and a slice of values to be used with it,
+// plus an apply func that assigns the updated timestamp to the struct. apply
+// must not be called until the update is known to have committed.
+func (d PostgresDriver) Update(data *DBObjectAddOn) (query string, vals []interface{}, apply func()) {
+	val := reflect.ValueOf(data.parent).Elem()
+	now := time.Now()
+	var pending []func()
+
+	var where string
+	var whereval interface{}
+
+	var columns []string
+	for i, col := range data.meta.cols {
+		if col.primary {
+			where = col.label
+			whereval = val.Field(i).Interface()
+			continue
+		}
+
+		field := val.Field(i)
+		colVal := field.Interface()
+
+		if col.updated {
+			colVal = now
+			pending = append(pending, func() { field.Set(reflect.ValueOf(now)) })
+		}
+
+		columns = append(columns, col.label)
+
+		if col.goType == GoTime {
+			vals = append(vals, colVal.(time.Time).Unix())
+			continue
+		}
+		vals = append(vals, colVal)
+	}
+
+	var sets []string
+	for i, c := range columns {
+		sets = append(sets, fmt.Sprintf("%s = %s", c, d.Placeholder(i+1)))
+	}
+	vals = append(vals, whereval)
+
+	query = fmt.Sprintf("UPDATE %s SET %s WHERE %s = %s", data.meta.table, strings.Join(sets, ", "), where, d.Placeholder(len(vals)))
+	apply = func() {
+		for _, p := range pending {
+			p()
+		}
+	}
+	return
+}
+
+// Select
+func (d PostgresDriver) Select(data *DBObjectAddOn, params Params) (query string, vals []interface{}) {
+	var where, order, limit string
+	if whereSQL, whereArgs := resolveWhere(d, params.Where); whereSQL != "" {
+		where = " WHERE " + whereSQL
+		vals = whereArgs
+	}
+	if params.OrderBy != "" {
+		order = " ORDER BY " + params.OrderBy
+	}
+	if params.Limit > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", params.Limit)
+	}
+
+	query = fmt.Sprintf("SELECT * FROM %s%s%s%s", data.meta.table, where, order, limit)
+	return
+}
+
+// Delete
+func (d PostgresDriver) Delete(data *DBObjectAddOn) (query string, vals interface{}) {
+	query = fmt.Sprintf("DELETE FROM %s WHERE %s = %s", data.meta.table, data.meta.cols[data.meta.pkey].label, d.Placeholder(1))
+	vals = reflect.ValueOf(data.parent).Elem().Field(data.meta.pkey).Interface()
+	return
+}
+
+// QuoteIdent quotes a column or table name for Postgres.
+func (d PostgresDriver) QuoteIdent(name string) string {
+	return `"` + name + `"`
+}
+
+// Placeholder returns the Postgres numbered bind placeholder for the i'th
+// argument, e.g. Placeholder(1) == "$1".
+func (d PostgresDriver) Placeholder(i int) string {
+	return fmt.Sprintf("$%d", i)
+}
+
+// ILike returns a case-insensitive LIKE template with two %s verbs for the
+// column expression and the placeholder, using Postgres's native ILIKE.
+func (d PostgresDriver) ILike() string {
+	return "%s ILIKE %s"
+}
+
+// GetTables lists the tables in the connected Postgres database's public schema.
+func (d PostgresDriver) GetTables(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("SELECT table_name FROM information_schema.tables WHERE table_schema = 'public'")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+// GetColumns introspects a table's columns via information_schema.
+func (d PostgresDriver) GetColumns(db *sql.DB, table string) ([]ColumnInfo, error) {
+	rows, err := db.Query(
+		"SELECT column_name, data_type, is_nullable FROM information_schema.columns WHERE table_schema = 'public' AND table_name = $1",
+		table,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cols []ColumnInfo
+	for rows.Next() {
+		var name, sqlType, nullable string
+		if err := rows.Scan(&name, &sqlType, &nullable); err != nil {
+			return nil, err
+		}
+		cols = append(cols, ColumnInfo{
+			Name:     name,
+			Type:     sqlType,
+			Nullable: nullable == "YES",
+		})
+	}
+	return cols, rows.Err()
+}
+
+// AlterAddColumn returns the ALTER TABLE statement that adds col to table.
+func (d PostgresDriver) AlterAddColumn(table string, col DBColumn) string {
+	def := ""
+	if col.def != "" {
+		def = " DEFAULT " + col.def
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s%s", table, col.label, col.sqlType, def)
+}
+
+// GetIndexes lists the names of every index already defined on table.
+func (d PostgresDriver) GetIndexes(db *sql.DB, table string) ([]string, error) {
+	rows, err := db.Query("SELECT indexname FROM pg_indexes WHERE schemaname = 'public' AND tablename = $1", table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// CreateIndex returns the statement that creates a (optionally unique) index
+// on table.
+func (d PostgresDriver) CreateIndex(table, name string, columns []string, unique bool) string {
+	kind := "INDEX"
+	if unique {
+		kind = "UNIQUE INDEX"
+	}
+	return fmt.Sprintf("CREATE %s %s ON %s (%s)", kind, name, table, strings.Join(columns, ", "))
+}
@@ -0,0 +1,193 @@
+package sqlez
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// normalizeKey collapses an interface{} holding any sized int into an int64
+// so FK values scanned from the driver (int64) compare equal to primary key
+// values read back off a populated struct (whatever int width the Go field
+// uses).
+func normalizeKey(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	default:
+		return v
+	}
+}
+
+// preloadRelations resolves each Preload path (e.g. "Author" or "Author.Org")
+// against objs, which must all share the same concrete DBObject type. Paths
+// sharing a first segment are batched into a single query for that relation.
+// Every batched query runs via d.getMany against q (ctx governs it too), so
+// a Preload issued from inside a Tx stays inside that Tx instead of
+// escaping to the shared connection pool.
+func preloadRelations(d *DB, ctx context.Context, q querier, objs []DBObject, paths []string) error {
+	if len(objs) == 0 || len(paths) == 0 {
+		return nil
+	}
+
+	grouped := make(map[string][]string)
+	var order []string
+	for _, p := range paths {
+		parts := strings.SplitN(p, ".", 2)
+		head := parts[0]
+		if _, ok := grouped[head]; !ok {
+			order = append(order, head)
+		}
+		if len(parts) == 2 {
+			grouped[head] = append(grouped[head], parts[1])
+		}
+	}
+
+	meta := objs[0].GetAddOn().meta
+	for _, head := range order {
+		col, ok := findRelation(meta, head)
+		if !ok {
+			return fmt.Errorf("sqlez: no such relation %q", head)
+		}
+
+		var err error
+		if col.reverse {
+			err = preloadReverse(d, ctx, q, objs, col, grouped[head])
+		} else {
+			err = preloadForeign(d, ctx, q, objs, col, grouped[head])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findRelation(meta *DBObjectMetadata, name string) (DBColumn, bool) {
+	for _, c := range meta.cols {
+		if c.foreign && c.fieldName == name {
+			return c, true
+		}
+	}
+	for _, c := range meta.relations {
+		if c.fieldName == name {
+			return c, true
+		}
+	}
+	return DBColumn{}, false
+}
+
+// newDBObject allocates a zero-value *T (where T is typ) and attaches it to
+// d, returning it as a DBObject.
+func newDBObject(d *DB, typ reflect.Type) DBObject {
+	obj := reflect.New(typ).Interface().(DBObject)
+	obj.Init(obj, d)
+	return obj
+}
+
+// preloadForeign batches a SELECT ... WHERE <foreignKey> IN (...) against
+// col.relatedType and assigns each result onto the matching parents' field.
+func preloadForeign(d *DB, ctx context.Context, q querier, objs []DBObject, col DBColumn, rest []string) error {
+	seen := make(map[interface{}]bool)
+	var fkValues []interface{}
+	for _, o := range objs {
+		v, ok := o.GetAddOn().fkValues[col.field]
+		if !ok {
+			continue
+		}
+		key := normalizeKey(v)
+		if !seen[key] {
+			seen[key] = true
+			fkValues = append(fkValues, v)
+		}
+	}
+	if len(fkValues) == 0 {
+		return nil
+	}
+
+	// getMany fills a slice of structs, not of pointers
+	sliceType := reflect.SliceOf(col.relatedType)
+	resultPtr := reflect.New(sliceType)
+
+	params := Params{Where: Q(col.foreignKey+"__in", fkValues)}
+	if err := d.getMany(ctx, params, resultPtr.Interface(), q); err != nil {
+		return err
+	}
+
+	results := resultPtr.Elem()
+	byKey := make(map[interface{}]reflect.Value, results.Len())
+	children := make([]DBObject, 0, results.Len())
+	for i := 0; i < results.Len(); i++ {
+		item := results.Index(i).Addr()
+		child := item.Interface().(DBObject)
+		pkey := child.GetAddOn().meta.pkey
+		byKey[normalizeKey(item.Elem().Field(pkey).Interface())] = item
+		children = append(children, child)
+	}
+
+	for _, o := range objs {
+		v, ok := o.GetAddOn().fkValues[col.field]
+		if !ok {
+			continue
+		}
+		if item, found := byKey[normalizeKey(v)]; found {
+			reflect.ValueOf(o).Elem().Field(col.field).Set(item)
+		}
+	}
+
+	return preloadRelations(d, ctx, q, children, rest)
+}
+
+// preloadReverse batches a SELECT ... WHERE <reverseFK> IN (...) against
+// col.relatedType and groups each result onto the owning parent's slice field.
+func preloadReverse(d *DB, ctx context.Context, q querier, objs []DBObject, col DBColumn, rest []string) error {
+	pkeys := make([]interface{}, 0, len(objs))
+	for _, o := range objs {
+		meta := o.GetAddOn().meta
+		pkeys = append(pkeys, reflect.ValueOf(o).Elem().Field(meta.pkey).Interface())
+	}
+
+	sample := newDBObject(d, col.relatedType)
+	fkField := -1
+	for _, c := range sample.GetAddOn().meta.cols {
+		if c.label == col.reverseFK {
+			fkField = c.field
+			break
+		}
+	}
+	if fkField < 0 {
+		return fmt.Errorf("sqlez: reverse relation column %q not found on %s", col.reverseFK, col.relatedType.Name())
+	}
+
+	// getMany fills a slice of structs, not of pointers
+	sliceType := reflect.SliceOf(col.relatedType)
+	resultPtr := reflect.New(sliceType)
+
+	params := Params{Where: Q(col.reverseFK+"__in", pkeys)}
+	if err := d.getMany(ctx, params, resultPtr.Interface(), q); err != nil {
+		return err
+	}
+
+	results := resultPtr.Elem()
+	byParent := make(map[interface{}][]reflect.Value)
+	children := make([]DBObject, 0, results.Len())
+	for i := 0; i < results.Len(); i++ {
+		item := results.Index(i).Addr()
+		key := normalizeKey(item.Elem().Field(fkField).Interface())
+		byParent[key] = append(byParent[key], item)
+		children = append(children, item.Interface().(DBObject))
+	}
+
+	for _, o := range objs {
+		meta := o.GetAddOn().meta
+		key := normalizeKey(reflect.ValueOf(o).Elem().Field(meta.pkey).Interface())
+		field := reflect.ValueOf(o).Elem().Field(col.field)
+		slc := reflect.MakeSlice(field.Type(), 0, len(byParent[key]))
+		slc = reflect.Append(slc, byParent[key]...)
+		field.Set(slc)
+	}
+
+	return preloadRelations(d, ctx, q, children, rest)
+}
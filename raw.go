@@ -0,0 +1,244 @@
+package sqlez
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// RawResult is a not-yet-run ad-hoc SQL query, built by (*DB).Raw or
+// (*DB).RawContext. It's the escape hatch for joins, aggregates, and
+// anything else outside of sqlez's struct-mapping layer.
+type RawResult struct {
+	db    *DB
+	ctx   context.Context
+	query string
+	args  []interface{}
+}
+
+// Raw prepares an ad-hoc SQL query for execution against d.
+func (d *DB) Raw(query string, args ...interface{}) *RawResult {
+	return d.RawContext(context.Background(), query, args...)
+}
+
+// RawContext is Raw with a caller-supplied context.
+func (d *DB) RawContext(ctx context.Context, query string, args ...interface{}) *RawResult {
+	return &RawResult{db: d, ctx: ctx, query: query, args: args}
+}
+
+func (r *RawResult) run() (*sql.Rows, error) {
+	return r.db.queryContext(r.ctx, r.db.DB, r.query, r.args...)
+}
+
+// ScanOne runs the query and scans its first row into ptr, which must be a
+// pointer to a DBObject. Returned columns are matched against the struct's
+// `db` tag labels by name - via the same metadata Init builds for Attach'd
+// types, rather than a separate parser - so the query need not select every
+// column or select them in declaration order.
+func (r *RawResult) ScanOne(ptr interface{}) error {
+	obj, ok := ptr.(DBObject)
+	if !ok {
+		return fmt.Errorf("sqlez: ScanOne expects a pointer to a DBObject, got %T", ptr)
+	}
+	if err := obj.Init(obj, r.db); err != nil {
+		return err
+	}
+
+	rows, err := r.run()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return fmt.Errorf("no rows returned matching criteria")
+	}
+	return scanRowByName(obj.GetAddOn(), rows)
+}
+
+// ScanAll runs the query and scans every row into ptrSlice, a pointer to a
+// slice of DBObject structs (e.g. *[]User) - the same shape GetMany
+// expects. A pointer to a slice of pointers is rejected.
+func (r *RawResult) ScanAll(ptrSlice interface{}) error {
+	slicePtr := reflect.ValueOf(ptrSlice)
+	if slicePtr.Kind() != reflect.Ptr {
+		return fmt.Errorf("expected pointer, got %s", slicePtr.Kind().String())
+	}
+	if k := slicePtr.Elem().Kind(); k != reflect.Slice {
+		return fmt.Errorf("expected slice, got %s", k.String())
+	}
+
+	elemType := slicePtr.Type().Elem().Elem()
+	sample, ok := reflect.New(elemType).Interface().(DBObject)
+	if !ok {
+		return fmt.Errorf("expected pointer to slice of DBObjects, got pointer to slice of %s", elemType.Kind().String())
+	}
+	if err := sample.Init(sample, r.db); err != nil {
+		return err
+	}
+
+	rows, err := r.run()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	slc := reflect.MakeSlice(slicePtr.Type().Elem(), 0, 0)
+	for rows.Next() {
+		no := reflect.New(elemType).Interface().(DBObject)
+		if err := no.Init(no, r.db); err != nil {
+			return err
+		}
+		if err := scanRowByName(no.GetAddOn(), rows); err != nil {
+			return err
+		}
+		slc = reflect.Append(slc, reflect.ValueOf(no).Elem())
+	}
+
+	slicePtr.Elem().Set(slc)
+	return rows.Err()
+}
+
+// scanRowByName scans the current row of rows into dest's struct fields,
+// matching each returned column against dest's metadata by label rather
+// than position, unlike populate (which assumes SELECT * in declaration
+// order).
+func scanRowByName(dest *DBObjectAddOn, rows *sql.Rows) error {
+	names, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	values := make([]interface{}, len(names))
+	pointers := make([]interface{}, len(names))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return err
+	}
+
+	field := reflect.ValueOf(dest.parent).Elem()
+	for i, name := range names {
+		col, ok := findColumnByLabel(dest.meta, name)
+		if !ok {
+			continue
+		}
+		if col.foreign {
+			// the Go field is a pointer to the referenced struct, not an
+			// int, so the scanned FK value is stashed for Preload rather
+			// than written to the field directly - same as populate.
+			if dest.fkValues == nil {
+				dest.fkValues = make(map[int]interface{})
+			}
+			dest.fkValues[col.field] = values[i]
+			continue
+		}
+		if err := assignColumnValue(field, col, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findColumnByLabel(meta *DBObjectMetadata, label string) (DBColumn, bool) {
+	for _, c := range meta.cols {
+		if c.label == label {
+			return c, true
+		}
+	}
+	return DBColumn{}, false
+}
+
+// ScanMap runs the query and returns every row as a column-name-to-value
+// map, for queries (joins, aggregates, ...) that don't map onto a single
+// registered struct.
+func (r *RawResult) ScanMap() ([]map[string]interface{}, error) {
+	rows, err := r.run()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []map[string]interface{}
+	for rows.Next() {
+		raw, err := scanRawBytes(rows, len(names))
+		if err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(names))
+		for i, name := range names {
+			row[name] = rawBytesToValue(raw[i])
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// ScanValues runs the query and returns every row as a slice of values, in
+// column order, for callers that want positional access without naming a
+// struct or map key.
+func (r *RawResult) ScanValues() ([][]interface{}, error) {
+	rows, err := r.run()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result [][]interface{}
+	for rows.Next() {
+		raw, err := scanRawBytes(rows, len(names))
+		if err != nil {
+			return nil, err
+		}
+
+		row := make([]interface{}, len(names))
+		for i := range names {
+			row[i] = rawBytesToValue(raw[i])
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+func scanRawBytes(rows *sql.Rows, n int) ([]sql.RawBytes, error) {
+	raw := make([]sql.RawBytes, n)
+	pointers := make([]interface{}, n)
+	for i := range raw {
+		pointers[i] = &raw[i]
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// rawBytesToValue copies b - which the driver is free to reuse on the next
+// Scan/Next/Close - into an int64, float64 or string, whichever its content
+// parses as. A nil b (SQL NULL) is returned as nil.
+func rawBytesToValue(b sql.RawBytes) interface{} {
+	if b == nil {
+		return nil
+	}
+	s := string(b)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
@@ -1,17 +1,40 @@
 package sqlez
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 )
 
 var (
-	MySQL  = MySQLDriver{}
-	Sqlite = SqliteDriver{}
+	MySQL    = MySQLDriver{}
+	Sqlite   = SqliteDriver{}
+	Postgres = PostgresDriver{}
 )
 
+// drivers holds every DBDriver registered with RegisterDriver, keyed by the
+// database/sql driver name (e.g. "mysql", "sqlite3", "postgres").
+var drivers = map[string]DBDriver{
+	"mysql":    MySQL,
+	"sqlite3":  Sqlite,
+	"postgres": Postgres,
+}
+
+// RegisterDriver registers a DBDriver under name, so third parties can add
+// support for new dialects (MSSQL, Oracle, ...) without forking sqlez.
+func RegisterDriver(name string, driver DBDriver) {
+	drivers[name] = driver
+}
+
+// GetDriver looks up a DBDriver previously registered with RegisterDriver.
+func GetDriver(name string) (driver DBDriver, ok bool) {
+	driver, ok = drivers[name]
+	return
+}
+
 type GoType int
 
 const (
@@ -27,28 +50,143 @@ type DBDriver interface {
 	GetDataType(reflect.Type) string
 	GetName() string
 	CreateTable(data *DBObjectMetadata) string
-	InsertIgnore(data *DBObjectAddOn, ignore bool) (string, []interface{})
-	Update(data *DBObjectAddOn) (string, []interface{})
-	Select(data *DBObjectAddOn, params Params) string
+	// InsertIgnore and Update also return an apply func that assigns the
+	// created/updated timestamps to the in-memory struct. Callers must only
+	// invoke it once the query is known to have committed, so a rolled-back
+	// Tx doesn't leave the struct out of sync with the database.
+	InsertIgnore(data *DBObjectAddOn, ignore bool) (string, []interface{}, func())
+	Update(data *DBObjectAddOn) (string, []interface{}, func())
+	// Select also returns the bind args contributed by params.Where, so a
+	// *Cond compiles to real placeholders instead of interpolated SQL.
+	Select(data *DBObjectAddOn, params Params) (string, []interface{})
 	Delete(data *DBObjectAddOn) (string, interface{})
+
+	// QuoteIdent, Placeholder and ILike let Cond compile to dialect-specific
+	// SQL: quoted identifiers, positional or numbered placeholders, and a
+	// case-insensitive LIKE template (with two %s verbs for the column
+	// expression and the placeholder, e.g. "LOWER(%s) LIKE LOWER(%s)").
+	QuoteIdent(name string) string
+	Placeholder(i int) string
+	ILike() string
+
+	// GetTables, GetColumns, AlterAddColumn, GetIndexes and CreateIndex back
+	// SyncSchema's live-schema introspection and reconciliation.
+	GetTables(db *sql.DB) ([]string, error)
+	GetColumns(db *sql.DB, table string) ([]ColumnInfo, error)
+	AlterAddColumn(table string, col DBColumn) string
+	// GetIndexes lists the names of every index already defined on table, so
+	// SyncSchema only creates the ones that are missing. Some dialects (e.g.
+	// MySQL) have no IF NOT EXISTS form for CREATE INDEX, so this check has
+	// to happen up front instead.
+	GetIndexes(db *sql.DB, table string) ([]string, error)
+	// CreateIndex returns the statement that creates a (optionally unique)
+	// index named name on table(columns...). Callers are expected to have
+	// already checked GetIndexes, so the returned statement need not guard
+	// against the index already existing.
+	CreateIndex(table, name string, columns []string, unique bool) string
+}
+
+// ColumnInfo describes a column as introspected from the live database
+// schema, as returned by DBDriver.GetColumns.
+type ColumnInfo struct {
+	Name     string
+	Type     string
+	Nullable bool
+	Primary  bool
 }
 
-// Params contains the parameters for the query
+// querier is satisfied by both *sql.DB and *sql.Tx, letting GetMany run
+// against either a plain connection or an in-flight transaction.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx, letting execContext run
+// against either a plain connection or an in-flight transaction.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// execContext runs query against ex, recording it as LastQuery and, if a
+// Logger is registered via SetLogger, timing it for LogQuery.
+func (d *DB) execContext(ctx context.Context, ex execer, query string, args ...interface{}) (sql.Result, error) {
+	d.setLastQuery(query)
+	start := time.Now()
+	res, err := ex.ExecContext(ctx, query, args...)
+	d.logQuery(ctx, query, args, time.Since(start), err)
+	return res, err
+}
+
+// queryContext runs query against q, recording it as LastQuery and, if a
+// Logger is registered via SetLogger, timing it for LogQuery.
+func (d *DB) queryContext(ctx context.Context, q querier, query string, args ...interface{}) (*sql.Rows, error) {
+	d.setLastQuery(query)
+	start := time.Now()
+	rows, err := q.QueryContext(ctx, query, args...)
+	d.logQuery(ctx, query, args, time.Since(start), err)
+	return rows, err
+}
+
+// Params contains the parameters for the query. Where accepts either a raw
+// SQL string (back-compat - used verbatim; GetExisting and GetMany bind no
+// args to it) or a *Cond built with Q/And/Or/IsNull, which compiles to
+// driver-specific SQL with real placeholders.
 type Params struct {
-	Where   string
+	Where   interface{}
 	OrderBy string
 	Limit   int
+	// Preload lists relation fields (by Go field name) to eager-load, e.g.
+	// []string{"Author", "Author.Org"} follows a `foreign` pointer field
+	// and then a `foreign`/`reverse` field on the loaded row. GetExisting
+	// and GetMany are the only fetch paths that honor it.
+	Preload []string
+}
+
+// resolveWhere compiles params.Where into a SQL fragment (without the
+// leading "WHERE") and its bind args, dispatching on the concrete type.
+func resolveWhere(driver DBDriver, where interface{}) (string, []interface{}) {
+	switch w := where.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return w, nil
+	case *Cond:
+		return w.compile(driver)
+	default:
+		return "", nil
+	}
 }
 
 // DB represents the sqlez database wrapper
 type DB struct {
-	DB        *sql.DB
-	driver    DBDriver
-	objects   map[reflect.Type]DBObjectMetadata
-	LastQuery string
-	dbTag     string
-	timeType  reflect.Type
-	// mutex     sync.Mutex
+	DB       *sql.DB
+	driver   DBDriver
+	objects  map[reflect.Type]DBObjectMetadata
+	dbTag    string
+	timeType reflect.Type
+
+	mu        sync.Mutex
+	lastQuery string
+
+	loggerMu sync.RWMutex
+	logger   Logger
+
+	hooksMu sync.RWMutex
+	hooks   map[reflect.Type]*typeHooks
+}
+
+// LastQuery returns the most recently executed query. Safe for concurrent
+// use, unlike reading a plain field off a DB shared across goroutines.
+func (d *DB) LastQuery() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastQuery
+}
+
+func (d *DB) setLastQuery(query string) {
+	d.mu.Lock()
+	d.lastQuery = query
+	d.mu.Unlock()
 }
 
 type DBObjectMetadata struct {
@@ -60,6 +198,13 @@ type DBObjectMetadata struct {
 	refreshOrderBy string
 	cols           []DBColumn
 	validated      bool
+	// uniqueTogether lists groups of column labels that should be synced as
+	// multi-column unique indexes, declared via a `unique_together` tag on
+	// the embedded DBObjectAddOn field.
+	uniqueTogether [][]string
+	// relations holds `reverse`-tagged fields, which have no column of
+	// their own and are only ever populated via Preload.
+	relations []DBColumn
 }
 
 type DBColumn struct {
@@ -77,6 +222,27 @@ type DBColumn struct {
 	def          string
 	json         bool
 	colProp      string
+	// indexName is set by an `index:<name>` tag option and synced as a
+	// single-column index by SyncSchema.
+	indexName string
+	// fieldName is the Go struct field name, used to match Preload entries
+	// against `foreign`/`reverse` relation fields.
+	fieldName string
+	// foreign is true for a `foreign` pointer-to-struct column; the column
+	// itself stores the referenced row's primary key.
+	foreign bool
+	// reverse is true for a `reverse` one-to-many back-reference; such a
+	// field has no column of its own and is never included in md.cols.
+	reverse bool
+	// reverseFK is the child table's FK column name, from `reverse:<col>`.
+	reverseFK string
+	// relatedType is the struct type on the other side of a foreign or
+	// reverse relation.
+	relatedType reflect.Type
+	// bigInt is true when the Go field backing a GoInt column is explicitly
+	// int64, rather than the platform int - PostgresDriver uses it to pick
+	// BIGSERIAL over SERIAL for an autoinc primary key.
+	bigInt bool
 }
 
 // Open initiates the connection to the database. It takes the same parameters as the database/sql package, and returns a sqlEZ DB struct. The contained *sql.DB is exported so you can make use of it directly.
@@ -98,7 +264,7 @@ func Open(driver DBDriver, dataSourceName string) (d *DB, err error) {
 }
 
 // Close closes the connection to the database
-func (d DB) Close() error {
+func (d *DB) Close() error {
 	return d.DB.Close()
 }
 
@@ -109,11 +275,28 @@ func (d *DB) SetDBTag(tag string) {
 
 // Attach connects an object to the database and makes it ready to be accessed
 func (d *DB) Attach(ptr DBObject) error {
-	ptr.Init(ptr, d)
-	return nil
+	return ptr.Init(ptr, d)
 }
 
-func (d *DB) GetMany(params Params, ptr interface{}) error {
+// Begin starts a transaction. The returned Tx mirrors the DBObject methods
+// (SaveNew, SaveExisting, Delete, GetExisting, Refresh) and GetMany but
+// routes them through the transaction instead of the shared connection.
+func (d *DB) Begin(ctx context.Context) (*Tx, error) {
+	sqlTx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{db: d, tx: sqlTx, ctx: ctx}, nil
+}
+
+// GetMany runs params against the database and populates ptr, which must be
+// a pointer to a slice of DBObjects. ctx governs the underlying query and can
+// be used to cancel or time out long-running scans.
+func (d *DB) GetMany(ctx context.Context, params Params, ptr interface{}) error {
+	return d.getMany(ctx, params, ptr, d.DB)
+}
+
+func (d *DB) getMany(ctx context.Context, params Params, ptr interface{}, q querier) error {
 	// make sure it's a Pointer
 	if reflect.ValueOf(ptr).Kind() != reflect.Ptr {
 		return fmt.Errorf("expected pointer, got %s", reflect.ValueOf(ptr).Kind().String())
@@ -125,16 +308,16 @@ func (d *DB) GetMany(params Params, ptr interface{}) error {
 	}
 
 	var query string
+	var args []interface{}
 	// make a new one for metadata and get the query
 	if obj, ok := reflect.New(reflect.TypeOf(ptr).Elem().Elem()).Interface().(DBObject); !ok {
 		return fmt.Errorf("expected pointer to slice of DBObjects, got pointer to slice of %s", reflect.TypeOf(ptr).Elem().Elem().Kind().String())
 	} else {
 		obj.Init(obj, d)
-		query = d.driver.Select(obj.GetAddOn(), params)
+		query, args = d.driver.Select(obj.GetAddOn(), params)
 	}
-	d.LastQuery = query
 
-	rows, err := d.DB.Query(query)
+	rows, err := d.queryContext(ctx, q, query, args...)
 	if err != nil {
 		return err
 	}
@@ -158,5 +341,17 @@ func (d *DB) GetMany(params Params, ptr interface{}) error {
 	}
 
 	reflect.ValueOf(ptr).Elem().Set(slc)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if len(params.Preload) > 0 {
+		final := reflect.ValueOf(ptr).Elem()
+		objs := make([]DBObject, final.Len())
+		for i := range objs {
+			objs[i] = final.Index(i).Addr().Interface().(DBObject)
+		}
+		return preloadRelations(d, ctx, q, objs, params.Preload)
+	}
+	return nil
 }
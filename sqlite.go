@@ -0,0 +1,300 @@
+package sqlez
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type SqliteDriver struct{}
+
+func (d SqliteDriver) GetDataType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "TEXT"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Bool:
+		return "INTEGER"
+	case reflect.Float32, reflect.Float64:
+		return "REAL"
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			return "INTEGER"
+		}
+	}
+	return "TEXT"
+}
+
+func (d SqliteDriver) GetName() string {
+	return "sqlite3"
+}
+
+func (d SqliteDriver) CreateTable(data *DBObjectMetadata) string {
+
+	var columns []string
+	for _, col := range data.cols {
+		pk := ""
+		if col.primary {
+			pk = " PRIMARY KEY"
+		}
+
+		auto := ""
+		if col.autoinc && col.primary {
+			auto = " AUTOINCREMENT"
+		}
+
+		def := ""
+		if col.def != "" {
+			def = " DEFAULT " + col.def
+		}
+
+		prop := ""
+		if col.colProp != "" {
+			prop = " " + col.colProp
+		}
+
+		columns = append(columns, fmt.Sprintf("%s %s%s%s%s%s", col.label, col.sqlType, pk, auto, def, prop))
+	}
+
+	if data.fkey >= 0 {
+		fk := data.cols[data.fkey]
+		columns = append(columns, fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s)", fk.label, fk.foreignTable, fk.foreignKey))
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (%s)", data.table, strings.Join(columns, ", "))
+}
+
+// InsertIgnore returns a query string and a slice of values to be used with
+// it, plus an apply func that assigns the created/updated timestamps to the
+// struct. apply must not be called until the insert is known to have
+// committed.
+func (d SqliteDriver) InsertIgnore(data *DBObjectAddOn, ignore bool) (query string, vals []interface{}, apply func()) {
+
+	val := reflect.ValueOf(data.parent).Elem()
+	now := time.Now()
+	var pending []func()
+
+	var columns []string
+	for i, col := range data.meta.cols {
+		if col.primary {
+			continue
+		}
+
+		field := val.Field(i)
+		colVal := field.Interface()
+
+		if col.created || col.updated {
+			colVal = now
+			pending = append(pending, func() { field.Set(reflect.ValueOf(now)) })
+		}
+
+		columns = append(columns, col.label)
+		if col.json {
+			if j, err := json.Marshal(colVal); err != nil {
+				fmt.Printf("err marshalling json: %s\n", err)
+			} else {
+				vals = append(vals, string(j))
+				continue
+			}
+		}
+
+		if col.goType == GoTime {
+			vals = append(vals, colVal.(time.Time).Unix())
+			continue
+		}
+
+		vals = append(vals, colVal)
+	}
+
+	ig := ""
+	if ignore {
+		ig = " OR IGNORE"
+	}
+
+	query = fmt.Sprintf("INSERT%s INTO %s (%s) VALUES (%s)", ig, data.meta.table, strings.Join(columns, ", "), strings.Repeat("?, ", len(columns)-1)+"?")
+	apply = func() {
+		for _, p := range pending {
+			p()
+		}
+	}
+	return
+}
+
+// Update returns a query string and a slice of values to be used with it,
+// plus an apply func that assigns the updated timestamp to the struct. apply
+// must not be called until the update is known to have committed.
+func (d SqliteDriver) Update(data *DBObjectAddOn) (query string, vals []interface{}, apply func()) {
+	val := reflect.ValueOf(data.parent).Elem()
+	now := time.Now()
+	var pending []func()
+
+	var where string
+	var whereval interface{}
+
+	var columns []string
+	for i, col := range data.meta.cols {
+		if col.primary {
+			where = col.label
+			whereval = val.Field(i).Interface()
+			continue
+		}
+
+		field := val.Field(i)
+		colVal := field.Interface()
+
+		if col.updated {
+			colVal = now
+			pending = append(pending, func() { field.Set(reflect.ValueOf(now)) })
+		}
+
+		columns = append(columns, col.label)
+
+		if col.goType == GoTime {
+			vals = append(vals, colVal.(time.Time).Unix())
+			continue
+		}
+		vals = append(vals, colVal)
+	}
+
+	vals = append(vals, whereval)
+	query = fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?", data.meta.table, strings.Join(columns, "= ?, ")+"= ?", where)
+	apply = func() {
+		for _, p := range pending {
+			p()
+		}
+	}
+	return
+}
+
+// Select
+func (d SqliteDriver) Select(data *DBObjectAddOn, params Params) (query string, vals []interface{}) {
+	var where, order, limit string
+	if whereSQL, whereArgs := resolveWhere(d, params.Where); whereSQL != "" {
+		where = " WHERE " + whereSQL
+		vals = whereArgs
+	}
+	if params.OrderBy != "" {
+		order = " ORDER BY " + params.OrderBy
+	}
+	if params.Limit > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", params.Limit)
+	}
+
+	query = fmt.Sprintf("SELECT * FROM %s%s%s%s", data.meta.table, where, order, limit)
+	return
+}
+
+// Delete
+func (d SqliteDriver) Delete(data *DBObjectAddOn) (query string, vals interface{}) {
+	query = fmt.Sprintf("DELETE FROM %s WHERE %s = ?", data.meta.table, data.meta.cols[data.meta.pkey].label)
+	vals = reflect.ValueOf(data.parent).Elem().Field(data.meta.pkey).Interface()
+	return
+}
+
+// QuoteIdent quotes a column or table name for SQLite.
+func (d SqliteDriver) QuoteIdent(name string) string {
+	return `"` + name + `"`
+}
+
+// Placeholder returns the SQLite bind placeholder for the i'th argument
+// (SQLite placeholders aren't numbered, so i is ignored).
+func (d SqliteDriver) Placeholder(i int) string {
+	return "?"
+}
+
+// ILike returns a case-insensitive LIKE template with two %s verbs for the
+// column expression and the placeholder, relying on SQLite's NOCASE
+// collation rather than wrapping both sides in LOWER().
+func (d SqliteDriver) ILike() string {
+	return "%s LIKE %s COLLATE NOCASE"
+}
+
+// GetTables lists the tables in the connected SQLite database.
+func (d SqliteDriver) GetTables(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+// GetColumns introspects a table's columns via PRAGMA table_info.
+func (d SqliteDriver) GetColumns(db *sql.DB, table string) ([]ColumnInfo, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", d.QuoteIdent(table)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cols []ColumnInfo
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return nil, err
+		}
+		cols = append(cols, ColumnInfo{
+			Name:     name,
+			Type:     colType,
+			Nullable: notNull == 0,
+			Primary:  pk > 0,
+		})
+	}
+	return cols, rows.Err()
+}
+
+// AlterAddColumn returns the ALTER TABLE statement that adds col to table.
+func (d SqliteDriver) AlterAddColumn(table string, col DBColumn) string {
+	def := ""
+	if col.def != "" {
+		def = " DEFAULT " + col.def
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s%s", table, col.label, col.sqlType, def)
+}
+
+// GetIndexes lists the names of every index already defined on table.
+func (d SqliteDriver) GetIndexes(db *sql.DB, table string) ([]string, error) {
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = ?", table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// CreateIndex returns the statement that creates a (optionally unique) index
+// on table.
+func (d SqliteDriver) CreateIndex(table, name string, columns []string, unique bool) string {
+	kind := "INDEX"
+	if unique {
+		kind = "UNIQUE INDEX"
+	}
+	return fmt.Sprintf("CREATE %s %s ON %s (%s)", kind, name, table, strings.Join(columns, ", "))
+}
@@ -0,0 +1,211 @@
+package sqlez
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SyncOpts controls SyncSchema's behavior.
+type SyncOpts struct {
+	// Force drops and recreates every attached table that already exists,
+	// instead of reconciling it in place.
+	Force bool
+	// Verbose prints every statement SyncSchema executes (DROP TABLE,
+	// CREATE TABLE, ALTER TABLE, CREATE INDEX). The queries that read the
+	// live schema are not printed.
+	Verbose bool
+}
+
+// SyncSchema reconciles the live database schema with the set of Attach'd
+// objects: missing tables are created, missing columns are added, and
+// declared indexes (including unique_together groups) are synced. It's
+// analogous to Django/Beego's `syncdb`, but never drops a column - dropping
+// is destructive enough that it's left to a real migration tool. The one
+// exception is opts.Force, which drops and recreates whole tables.
+func (d *DB) SyncSchema(opts SyncOpts) error {
+	tables, err := d.driver.GetTables(d.DB)
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		exists[t] = true
+	}
+
+	for _, meta := range sortByFKDependency(d.objects) {
+		if err := d.syncTable(meta, exists, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortByFKDependency orders objects' metadata so that any table referenced
+// by another table's foreign key is created before its dependent, via a
+// topological sort of the FK graph. Ranging over d.objects directly (a Go
+// map) would otherwise hand CreateTable a different order every run, and
+// MySQL/Postgres both reject a CREATE TABLE whose FOREIGN KEY references a
+// table that doesn't exist yet.
+func sortByFKDependency(objects map[reflect.Type]DBObjectMetadata) []*DBObjectMetadata {
+	byTable := make(map[string]*DBObjectMetadata, len(objects))
+	for _, meta := range objects {
+		m := meta
+		byTable[m.table] = &m
+	}
+
+	tables := make([]string, 0, len(byTable))
+	for t := range byTable {
+		tables = append(tables, t)
+	}
+	sort.Strings(tables)
+
+	adj := make(map[string][]string)
+	indegree := make(map[string]int, len(tables))
+	for _, t := range tables {
+		meta := byTable[t]
+		if meta.fkey < 0 {
+			continue
+		}
+		dep := meta.cols[meta.fkey].foreignTable
+		if _, ok := byTable[dep]; ok && dep != t {
+			adj[dep] = append(adj[dep], t)
+			indegree[t]++
+		}
+	}
+
+	var queue []string
+	for _, t := range tables {
+		if indegree[t] == 0 {
+			queue = append(queue, t)
+		}
+	}
+
+	var order []string
+	for len(queue) > 0 {
+		sort.Strings(queue)
+		t := queue[0]
+		queue = queue[1:]
+		order = append(order, t)
+
+		next := append([]string(nil), adj[t]...)
+		sort.Strings(next)
+		for _, n := range next {
+			indegree[n]--
+			if indegree[n] == 0 {
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	// A cycle would leave some tables unvisited - shouldn't happen, since
+	// Init rejects a foreign field whose type hasn't been attached yet, but
+	// append anything left over rather than silently dropping it.
+	seen := make(map[string]bool, len(order))
+	for _, t := range order {
+		seen[t] = true
+	}
+	for _, t := range tables {
+		if !seen[t] {
+			order = append(order, t)
+		}
+	}
+
+	result := make([]*DBObjectMetadata, len(order))
+	for i, t := range order {
+		result[i] = byTable[t]
+	}
+	return result
+}
+
+func (d *DB) syncTable(meta *DBObjectMetadata, exists map[string]bool, opts SyncOpts) error {
+	if exists[meta.table] && opts.Force {
+		if err := d.run(fmt.Sprintf("DROP TABLE %s", meta.table), opts); err != nil {
+			return err
+		}
+		exists[meta.table] = false
+	}
+
+	if !exists[meta.table] {
+		if err := d.run(d.driver.CreateTable(meta), opts); err != nil {
+			return err
+		}
+		exists[meta.table] = true
+	} else {
+		cols, err := d.driver.GetColumns(d.DB, meta.table)
+		if err != nil {
+			return err
+		}
+		have := make(map[string]bool, len(cols))
+		for _, c := range cols {
+			have[c.Name] = true
+		}
+
+		for _, col := range meta.cols {
+			if have[col.label] {
+				continue
+			}
+			if err := d.run(d.driver.AlterAddColumn(meta.table, col), opts); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Index creation has no portable IF NOT EXISTS form (MySQL's CREATE
+	// INDEX doesn't have one), so existing indexes are listed up front and
+	// checked by name instead.
+	indexes, err := d.driver.GetIndexes(d.DB, meta.table)
+	if err != nil {
+		return err
+	}
+	haveIndex := make(map[string]bool, len(indexes))
+	for _, idx := range indexes {
+		haveIndex[idx] = true
+	}
+
+	for _, col := range meta.cols {
+		if col.indexName == "" || haveIndex[col.indexName] {
+			continue
+		}
+		if err := d.run(d.driver.CreateIndex(meta.table, col.indexName, []string{col.label}, false), opts); err != nil {
+			return err
+		}
+	}
+
+	for _, group := range meta.uniqueTogether {
+		name := fmt.Sprintf("uq_%s_%s", meta.table, strings.Join(group, "_"))
+		if haveIndex[name] {
+			continue
+		}
+		if err := d.run(d.driver.CreateIndex(meta.table, name, group, true), opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *DB) run(query string, opts SyncOpts) error {
+	if opts.Verbose {
+		fmt.Println(query)
+	}
+	_, err := d.execContext(context.Background(), d.DB, query)
+	return err
+}
+
+// parseStructTag reads the struct-level directives carried by the
+// `db` tag on the embedded DBObjectAddOn field. Currently this is just
+// unique_together, e.g. `db:"unique_together:author_id+org_id;name+org_id"`.
+func parseStructTag(md *DBObjectMetadata, tag string) {
+	for _, directive := range strings.Split(tag, ",") {
+		vv := strings.SplitN(directive, ":", 2)
+		if len(vv) != 2 || vv[0] != "unique_together" {
+			continue
+		}
+		for _, group := range strings.Split(vv[1], ";") {
+			md.uniqueTogether = append(md.uniqueTogether, strings.Split(group, "+"))
+		}
+	}
+}
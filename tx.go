@@ -0,0 +1,219 @@
+package sqlez
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Tx wraps a database/sql transaction. It exposes the same operations as
+// DBObjectAddOn (SaveNew, SaveExisting, Delete, GetExisting, Refresh) and
+// (*DB).GetMany, but executed against the transaction instead of the shared *DB
+// connection, and taking the DBObject as an explicit argument since a Tx
+// isn't embedded in the struct the way DBObjectAddOn is.
+//
+// created/updated timestamp assignments and after-hooks are both deferred
+// until Commit, so a Rollback never leaves the in-memory struct out of sync
+// with the database, or lets an audit/notification hook fire for a write
+// that never became durable.
+type Tx struct {
+	db           *DB
+	tx           *sql.Tx
+	ctx          context.Context
+	pending      []func()
+	pendingHooks []pendingHook
+}
+
+// pendingHook is an after-hook queued by SaveNew/SaveExisting/Delete,
+// waiting on Commit to actually run.
+type pendingHook struct {
+	kind hookKind
+	obj  DBObject
+}
+
+// Commit commits the transaction, applies any deferred timestamp
+// assignments made by SaveNew/SaveExisting during the transaction, and runs
+// any after-hooks queued by SaveNew/SaveExisting/Delete, in the order they
+// were queued.
+func (t *Tx) Commit() error {
+	if err := t.tx.Commit(); err != nil {
+		return err
+	}
+	for _, apply := range t.pending {
+		apply()
+	}
+	t.pending = nil
+
+	hooks := t.pendingHooks
+	t.pendingHooks = nil
+	for _, h := range hooks {
+		if err := t.db.runHooks(t.ctx, h.obj, h.kind); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rollback aborts the transaction. Deferred timestamp assignments and
+// after-hooks are discarded rather than applied/run, so the in-memory
+// structs are left as they were before the transaction began and no hook
+// ever sees a write that didn't happen.
+func (t *Tx) Rollback() error {
+	t.pending = nil
+	t.pendingHooks = nil
+	return t.tx.Rollback()
+}
+
+func (t *Tx) defer_(apply func()) {
+	if apply != nil {
+		t.pending = append(t.pending, apply)
+	}
+}
+
+// deferHook queues an after-hook to run once the transaction commits.
+func (t *Tx) deferHook(kind hookKind, obj DBObject) {
+	t.pendingHooks = append(t.pendingHooks, pendingHook{kind: kind, obj: obj})
+}
+
+// SaveNew mirrors (*DBObjectAddOn).SaveNew, executed within the transaction.
+func (t *Tx) SaveNew(obj DBObject, ignore bool) (n int, err error) {
+	d := obj.GetAddOn()
+	if err = validateMetadata(d.meta); err != nil {
+		return
+	}
+
+	if err = t.db.runHooks(t.ctx, obj, hookBeforeInsert); err != nil {
+		return
+	}
+
+	query, vals, apply := d.db.driver.InsertIgnore(d, ignore)
+
+	var res sql.Result
+	res, err = t.db.execContext(t.ctx, t.tx, query, vals...)
+	if err != nil {
+		return
+	}
+	if nr, e := res.RowsAffected(); e == nil {
+		n = int(nr)
+	}
+	t.defer_(apply)
+	t.deferHook(hookAfterInsert, obj)
+	return
+}
+
+// SaveExisting mirrors (*DBObjectAddOn).SaveExisting, executed within the transaction.
+func (t *Tx) SaveExisting(obj DBObject) (n int, err error) {
+	d := obj.GetAddOn()
+	if err = validateMetadata(d.meta); err != nil {
+		return
+	}
+
+	if err = t.db.runHooks(t.ctx, obj, hookBeforeUpdate); err != nil {
+		return
+	}
+
+	query, vals, apply := d.db.driver.Update(d)
+
+	var res sql.Result
+	res, err = t.db.execContext(t.ctx, t.tx, query, vals...)
+	if err != nil {
+		return
+	}
+	if nr, e := res.RowsAffected(); e == nil {
+		n = int(nr)
+	}
+	t.defer_(apply)
+	t.deferHook(hookAfterUpdate, obj)
+	return
+}
+
+// Delete mirrors (*DBObjectAddOn).Delete, executed within the transaction.
+func (t *Tx) Delete(obj DBObject) (n int, err error) {
+	d := obj.GetAddOn()
+	if err = validateMetadata(d.meta); err != nil {
+		return
+	}
+
+	if err = t.db.runHooks(t.ctx, obj, hookBeforeDelete); err != nil {
+		return
+	}
+
+	query, val := d.db.driver.Delete(d)
+
+	var res sql.Result
+	res, err = t.db.execContext(t.ctx, t.tx, query, val)
+	if err != nil {
+		return
+	}
+	if nr, e := res.RowsAffected(); e == nil {
+		n = int(nr)
+	}
+	t.deferHook(hookAfterDelete, obj)
+	return
+}
+
+// GetExisting mirrors (*DBObjectAddOn).GetExisting, executed within the transaction.
+func (t *Tx) GetExisting(obj DBObject, params Params) error {
+	d := obj.GetAddOn()
+	if err := validateMetadata(d.meta); err != nil {
+		return err
+	}
+	params.Limit = 1
+
+	query, args := d.db.driver.Select(d, params)
+
+	rows, err := t.db.queryContext(t.ctx, t.tx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return fmt.Errorf("no rows returned matching criteria")
+	}
+
+	if _, err := d.populate(rows); err != nil {
+		return err
+	}
+
+	if len(params.Preload) > 0 {
+		return preloadRelations(t.db, t.ctx, t.tx, []DBObject{obj}, params.Preload)
+	}
+	return nil
+}
+
+// Refresh mirrors (*DBObjectAddOn).Refresh, executed within the transaction.
+func (t *Tx) Refresh(obj DBObject) error {
+	d := obj.GetAddOn()
+	if err := validateMetadata(d.meta); err != nil {
+		return err
+	}
+
+	p := Params{
+		Where:   fmt.Sprintf("%s = %s", d.meta.cols[d.meta.pkey].label, t.db.driver.Placeholder(1)),
+		Limit:   1,
+		OrderBy: d.meta.refreshOrderBy,
+	}
+
+	query, args := d.db.driver.Select(d, p)
+
+	pk := reflect.ValueOf(d.parent).Elem().Field(d.meta.pkey).Interface()
+	rows, err := t.db.queryContext(t.ctx, t.tx, query, append(args, pk)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return fmt.Errorf("no rows returned")
+	}
+
+	_, err = d.populate(rows)
+	return err
+}
+
+// GetMany mirrors (*DB).GetMany, executed within the transaction.
+func (t *Tx) GetMany(params Params, ptr interface{}) error {
+	return t.db.getMany(t.ctx, params, ptr, t.tx)
+}